@@ -0,0 +1,62 @@
+package znet
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// CertProvider resolves a TLS certificate per handshake, WithCertProvider
+// wires one into SetAddr/AddAddr instead of a static Cert/Key pair or a
+// fully built *tls.Config.
+type CertProvider interface {
+	GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error)
+}
+
+// acmeHTTPHandler is implemented by CertProviders that need to answer the
+// ACME HTTP-01 challenge over TlsCfg.HTTPAddr before falling back to the
+// normal handler (znet/certprovider/acmeprovider.Provider does).
+type acmeHTTPHandler interface {
+	HTTPHandler(fallback http.Handler) http.Handler
+}
+
+// WithCertProvider returns a TlsCfg that resolves certificates through p,
+// for use with SetAddr/AddAddr.
+func WithCertProvider(p CertProvider) TlsCfg {
+	return TlsCfg{CertProvider: p}
+}
+
+// SNICertProvider resolves certificates from an in-memory map keyed by SNI
+// server name, Add may be called concurrently with handshakes. A cert
+// stored under the empty server name is served to clients that send no SNI.
+type SNICertProvider struct {
+	mu    sync.RWMutex
+	certs map[string]*tls.Certificate
+}
+
+// NewSNICertProvider returns an empty SNICertProvider, populate it with Add.
+func NewSNICertProvider() *SNICertProvider {
+	return &SNICertProvider{certs: make(map[string]*tls.Certificate)}
+}
+
+// Add registers cert for serverName, replacing any certificate already
+// registered for it.
+func (p *SNICertProvider) Add(serverName string, cert *tls.Certificate) {
+	p.mu.Lock()
+	p.certs[serverName] = cert
+	p.mu.Unlock()
+}
+
+// GetCertificate GetCertificate
+func (p *SNICertProvider) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if cert, ok := p.certs[hello.ServerName]; ok {
+		return cert, nil
+	}
+	if cert, ok := p.certs[""]; ok {
+		return cert, nil
+	}
+	return nil, fmt.Errorf("znet: no certificate for %q", hello.ServerName)
+}
@@ -0,0 +1,48 @@
+// Package acmeprovider implements znet.CertProvider by obtaining and
+// automatically renewing certificates from an ACME CA (e.g. Let's
+// Encrypt) via golang.org/x/crypto/acme/autocert, restricted to an
+// explicit host whitelist so a stray SNI name can't trigger a rogue
+// issuance request.
+package acmeprovider
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/sohaha/zlsgo/znet"
+)
+
+// Provider obtains and renews certificates via ACME using
+// golang.org/x/crypto/acme/autocert, restricted to the given host
+// whitelist, with its account and certificate cache kept under cacheDir.
+type Provider struct {
+	manager *autocert.Manager
+}
+
+var _ znet.CertProvider = (*Provider)(nil)
+
+// New returns a Provider that only issues for hosts, caching everything it
+// obtains under cacheDir.
+func New(cacheDir string, hosts ...string) *Provider {
+	return &Provider{
+		manager: &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(cacheDir),
+			HostPolicy: autocert.HostWhitelist(hosts...),
+		},
+	}
+}
+
+// GetCertificate GetCertificate
+func (p *Provider) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return p.manager.GetCertificate(hello)
+}
+
+// HTTPHandler serves the ACME HTTP-01 challenge and falls back to fallback
+// for every other request, Run wires this in automatically over
+// TlsCfg.HTTPAddr when CertProvider implements it.
+func (p *Provider) HTTPHandler(fallback http.Handler) http.Handler {
+	return p.manager.HTTPHandler(fallback)
+}
@@ -0,0 +1,103 @@
+// Package fileprovider implements znet.CertProvider on top of a Cert/Key
+// pair stored on disk, reloading it whenever the underlying files change
+// so a renewed certificate (e.g. dropped in by certbot) takes effect
+// without restarting the Engine.
+package fileprovider
+
+import (
+	"crypto/tls"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/sohaha/zlsgo/znet"
+)
+
+// Provider loads a Cert/Key pair from disk and reloads it whenever either
+// file changes, using fsnotify.
+type Provider struct {
+	certFile, keyFile string
+	mu                sync.RWMutex
+	cert              *tls.Certificate
+	watcher           *fsnotify.Watcher
+}
+
+var _ znet.CertProvider = (*Provider)(nil)
+
+// New loads certFile/keyFile and starts watching them for changes, call
+// Close to stop watching.
+func New(certFile, keyFile string) (*Provider, error) {
+	p := &Provider{certFile: certFile, keyFile: keyFile}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// Watch the parent directories rather than the files themselves so an
+	// atomic rename/symlink swap is still picked up, an inotify watch on the
+	// old file's inode would otherwise go silent after the swap. A
+	// Kubernetes Secret volume update retargets the directory's "..data"
+	// symlink rather than touching certFile/keyFile directly, so watch
+	// reacts to any create/write/rename in the directory instead of
+	// filtering by exact file name.
+	dirs := map[string]struct{}{filepath.Dir(certFile): {}, filepath.Dir(keyFile): {}}
+	for dir := range dirs {
+		if err := w.Add(dir); err != nil {
+			_ = w.Close()
+			return nil, err
+		}
+	}
+
+	p.watcher = w
+	go p.watch()
+	return p, nil
+}
+
+func (p *Provider) reload() error {
+	cert, err := tls.LoadX509KeyPair(p.certFile, p.keyFile)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.cert = &cert
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *Provider) watch() {
+	for {
+		select {
+		case ev, ok := <-p.watcher.Events:
+			if !ok {
+				return
+			}
+			if ev.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if err := p.reload(); err != nil {
+				znet.Log.Errorf("CertProvider: reload %s: %s\n", p.certFile, err)
+			}
+		case err, ok := <-p.watcher.Errors:
+			if !ok {
+				return
+			}
+			znet.Log.Errorf("CertProvider: watch %s: %s\n", p.certFile, err)
+		}
+	}
+}
+
+// GetCertificate GetCertificate
+func (p *Provider) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.cert, nil
+}
+
+// Close stops watching certFile/keyFile for changes.
+func (p *Provider) Close() error {
+	return p.watcher.Close()
+}
@@ -0,0 +1,39 @@
+package znet
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestSNICertProviderGetCertificate(t *testing.T) {
+	p := NewSNICertProvider()
+	example := &tls.Certificate{}
+	fallback := &tls.Certificate{}
+	p.Add("example.com", example)
+	p.Add("", fallback)
+
+	cert, err := p.GetCertificate(&tls.ClientHelloInfo{ServerName: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert != example {
+		t.Error("GetCertificate did not return the cert registered for the matching SNI name")
+	}
+
+	cert, err = p.GetCertificate(&tls.ClientHelloInfo{ServerName: "other.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert != fallback {
+		t.Error("GetCertificate did not fall back to the empty-name cert for an unknown SNI name")
+	}
+}
+
+func TestSNICertProviderGetCertificateNoMatch(t *testing.T) {
+	p := NewSNICertProvider()
+	p.Add("example.com", &tls.Certificate{})
+
+	if _, err := p.GetCertificate(&tls.ClientHelloInfo{ServerName: "other.example.com"}); err == nil {
+		t.Error("GetCertificate should error when neither the SNI name nor a fallback is registered")
+	}
+}
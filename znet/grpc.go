@@ -0,0 +1,50 @@
+package znet
+
+import "net/http"
+
+// GRPCGatewayHandler is a pluggable dispatcher Run checks ahead of the znet
+// router so gRPC and/or grpc-gateway traffic can share the same listener and
+// port, wire one in with SetGRPCHandler. See znet/grpcgateway for the
+// default implementation backed by google.golang.org/grpc and
+// grpc-ecosystem/grpc-gateway.
+type GRPCGatewayHandler interface {
+	// Handles reports whether r should be dispatched to this handler
+	// instead of the znet router.
+	Handles(r *http.Request) bool
+	http.Handler
+}
+
+// SetGRPCHandler wires h into Run's dispatch ahead of the znet router, see
+// znet/grpcgateway.New for the default implementation.
+func (e *Engine) SetGRPCHandler(h GRPCGatewayHandler) {
+	e.grpcHandler = h
+}
+
+// httpHandler is the http.Handler Run hands to http.Server, it dispatches to
+// the registered GRPCGatewayHandler, if any, before falling through to the
+// znet router.
+func (e *Engine) httpHandler(isTls bool) http.Handler {
+	if e.grpcHandler == nil {
+		return e
+	}
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if e.grpcHandler.Handles(r) {
+			e.grpcHandler.ServeHTTP(w, r)
+			return
+		}
+		e.ServeHTTP(w, r)
+	})
+
+	if !isTls {
+		// cleartext h2c support, if the handler needs it (e.g. so gRPC
+		// still works without TLS+ALPN), is opt-in via this optional method
+		// instead of a core znet dependency on golang.org/x/net/http2.
+		if wrapper, ok := e.grpcHandler.(interface {
+			Wrap(next http.Handler) http.Handler
+		}); ok {
+			return wrapper.Wrap(h)
+		}
+	}
+	return h
+}
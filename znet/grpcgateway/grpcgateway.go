@@ -0,0 +1,109 @@
+// Package grpcgateway implements znet.GRPCGatewayHandler, dispatching
+// requests sharing a znet.Engine's port between a registered *grpc.Server
+// and a grpc-gateway REST mux by ProtoMajor/Content-Type, so a single
+// listener can serve gRPC, its REST transcoding, and ordinary HTTP routes
+// together.
+package grpcgateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+
+	"github.com/sohaha/zlsgo/znet"
+)
+
+// errGatewayPrefixNotSet is returned by RegisterGateway when the caller never
+// configured a prefix narrower than "/" with SetGatewayPrefix, mounting the
+// gateway there would match every request and starve the znet router.
+var errGatewayPrefixNotSet = errors.New("grpcgateway: call SetGatewayPrefix with a prefix other than \"/\" before RegisterGateway")
+
+// Handler dispatches gRPC and grpc-gateway traffic sharing a znet.Engine's
+// port, wire one in with Engine.SetGRPCHandler.
+type Handler struct {
+	grpcServer    *grpc.Server
+	gatewayMux    http.Handler
+	gatewayPrefix string
+}
+
+var _ znet.GRPCGatewayHandler = (*Handler)(nil)
+
+// New returns an empty Handler, register services with RegisterGRPC and/or
+// RegisterGateway before wiring it into an Engine with SetGRPCHandler.
+func New() *Handler {
+	return &Handler{}
+}
+
+// RegisterGRPC lazily builds the Handler's *grpc.Server and lets register
+// wire services onto it. Once registered, the Engine it's wired into
+// dispatches any request with ProtoMajor 2 and an "application/grpc"
+// Content-Type to it instead of the znet router, so gRPC and HTTP share the
+// same listener and port.
+func (h *Handler) RegisterGRPC(register func(*grpc.Server)) *grpc.Server {
+	if h.grpcServer == nil {
+		h.grpcServer = grpc.NewServer()
+	}
+	register(h.grpcServer)
+	return h.grpcServer
+}
+
+// RegisterGateway mounts a grpc-gateway *runtime.ServeMux built by register
+// under the prefix configured with SetGatewayPrefix, so the same service
+// definitions RegisterGRPC exposes also serve REST/JSON on this port
+// alongside the znet router. Call SetGatewayPrefix with a prefix other than
+// "/" first, otherwise RegisterGateway returns an error instead of silently
+// swallowing every request the znet router would otherwise handle.
+func (h *Handler) RegisterGateway(ctx context.Context, register func(mux *runtime.ServeMux) error) error {
+	if h.gatewayPrefix == "" || h.gatewayPrefix == "/" {
+		return errGatewayPrefixNotSet
+	}
+	mux := runtime.NewServeMux()
+	if err := register(mux); err != nil {
+		return err
+	}
+	h.gatewayMux = mux
+	return nil
+}
+
+// SetGatewayPrefix configures the path prefix RegisterGateway's mux answers
+// under, call it with a prefix other than "/" before RegisterGateway.
+func (h *Handler) SetGatewayPrefix(prefix string) {
+	h.gatewayPrefix = prefix
+}
+
+// Handles reports whether r is gRPC traffic or falls under the gateway
+// prefix, see znet.GRPCGatewayHandler.
+func (h *Handler) Handles(r *http.Request) bool {
+	if h.grpcServer != nil && r.ProtoMajor == 2 &&
+		strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		return true
+	}
+	return h.gatewayMux != nil && strings.HasPrefix(r.URL.Path, h.gatewayPrefix)
+}
+
+// ServeHTTP implements znet.GRPCGatewayHandler, callers should route through
+// Handles first, as the Engine's dispatcher does.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.grpcServer != nil && r.ProtoMajor == 2 &&
+		strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		h.grpcServer.ServeHTTP(w, r)
+		return
+	}
+	h.gatewayMux.ServeHTTP(w, r)
+}
+
+// Wrap adds h2c support on cleartext listeners so gRPC still works without
+// TLS+ALPN, the Engine's dispatcher calls it when present instead of
+// depending on golang.org/x/net/http2 itself.
+func (h *Handler) Wrap(next http.Handler) http.Handler {
+	if h.grpcServer == nil {
+		return next
+	}
+	return h2c.NewHandler(next, &http2.Server{})
+}
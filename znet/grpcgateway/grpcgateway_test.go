@@ -0,0 +1,64 @@
+package grpcgateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+func TestHandlesGatewayPrefix(t *testing.T) {
+	h := New()
+	h.gatewayMux = http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	h.SetGatewayPrefix("/api/")
+
+	cases := map[string]bool{
+		"/api/widgets": true,
+		"/healthz":     false,
+	}
+	for path, want := range cases {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if got := h.Handles(r); got != want {
+			t.Errorf("Handles(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestRegisterGatewayRejectsDefaultPrefix(t *testing.T) {
+	noop := func(mux *runtime.ServeMux) error { return nil }
+
+	h := New()
+	if err := h.RegisterGateway(context.Background(), noop); err == nil {
+		t.Error("RegisterGateway with no prefix configured should return an error")
+	}
+
+	h.SetGatewayPrefix("/")
+	if err := h.RegisterGateway(context.Background(), noop); err == nil {
+		t.Error("RegisterGateway with prefix \"/\" should return an error")
+	}
+}
+
+func TestRegisterGatewayMountsUnderExplicitPrefix(t *testing.T) {
+	h := New()
+	h.SetGatewayPrefix("/api/")
+
+	if err := h.RegisterGateway(context.Background(), func(mux *runtime.ServeMux) error { return nil }); err != nil {
+		t.Fatalf("RegisterGateway returned %v", err)
+	}
+	if h.gatewayMux == nil {
+		t.Error("RegisterGateway did not set gatewayMux")
+	}
+}
+
+func TestWrapIsNoopWithoutGRPCServer(t *testing.T) {
+	h := New()
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+	got := h.Wrap(next)
+	if reflect.ValueOf(got).Pointer() != reflect.ValueOf(next).Pointer() {
+		t.Error("Wrap should return next unchanged when no gRPC server is registered")
+	}
+}
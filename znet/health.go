@@ -0,0 +1,226 @@
+package znet
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// Status mirrors grpc's health/grpc_health_v1 serving states.
+	Status int32
+	// Probe is a named readiness check HealthChecker.Register adds.
+	Probe struct {
+		name    string
+		fn      func(ctx context.Context) error
+		timeout time.Duration
+	}
+	// HealthChecker is the Engine's health subsystem, it tracks a
+	// per-service Status (the empty service name stands for the Engine as
+	// a whole) and a set of readiness Probes, and answers /healthz,
+	// /livez, /readyz and an SSE /healthz/watch endpoint.
+	HealthChecker struct {
+		mu       sync.RWMutex
+		statuses map[string]Status
+		probes   []Probe
+		watchers map[chan Status]struct{}
+		engine   *Engine
+	}
+)
+
+const (
+	// StatusUnknown the service's status has never been reported
+	StatusUnknown Status = iota
+	// StatusServing the service is accepting traffic
+	StatusServing
+	// StatusNotServing the service should be taken out of rotation
+	StatusNotServing
+
+	defaultProbeTimeout = 3 * time.Second
+
+	healthzPath      = "/healthz"
+	healthzWatchPath = "/healthz/watch"
+	livezPath        = "/livez"
+	readyzPath       = "/readyz"
+)
+
+// String String
+func (s Status) String() string {
+	switch s {
+	case StatusServing:
+		return "SERVING"
+	case StatusNotServing:
+		return "NOT_SERVING"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Health returns the Engine's HealthChecker, registering /healthz, /livez,
+// /readyz and /healthz/watch the first time it is called.
+func (e *Engine) Health() *HealthChecker {
+	if e.health == nil {
+		e.health = newHealthChecker(e)
+	}
+	return e.health
+}
+
+func newHealthChecker(e *Engine) *HealthChecker {
+	h := &HealthChecker{
+		statuses: map[string]Status{"": StatusServing},
+		watchers: map[chan Status]struct{}{},
+		engine:   e,
+	}
+	e.GET(healthzPath, h.handleHealthz)
+	e.GET(healthzWatchPath, h.handleWatch)
+	e.GET(livezPath, h.handleLivez)
+	e.GET(readyzPath, h.handleReadyz)
+	return h
+}
+
+// SetServingStatus records status for service, the empty service name
+// stands for the Engine overall, and notifies anyone watching
+// /healthz/watch.
+func (h *HealthChecker) SetServingStatus(service string, status Status) {
+	h.mu.Lock()
+	h.statuses[service] = status
+	h.mu.Unlock()
+	h.broadcast(status)
+}
+
+// Register adds a named readiness probe /readyz runs on every request, fn
+// has timeout to return before it counts as failed (defaultProbeTimeout if
+// omitted).
+func (h *HealthChecker) Register(name string, fn func(ctx context.Context) error, timeout ...time.Duration) {
+	t := defaultProbeTimeout
+	if len(timeout) > 0 {
+		t = timeout[0]
+	}
+	h.mu.Lock()
+	h.probes = append(h.probes, Probe{name: name, fn: fn, timeout: t})
+	h.mu.Unlock()
+}
+
+// Middleware returns a MiddlewareFunc that answers 503 to any request
+// outside the health endpoints while the overall status is NOT_SERVING, so
+// load balancers drain traffic before Run's shutdown timeout elapses.
+func (h *HealthChecker) Middleware() MiddlewareFunc {
+	return func(c *Context, fn HandlerFunc) {
+		if isHealthPath(c.Request.URL.Path) || h.status("") != StatusNotServing {
+			fn(c)
+			return
+		}
+		c.Code = http.StatusServiceUnavailable
+		c.Writer.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
+func (h *HealthChecker) status(service string) Status {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	s, ok := h.statuses[service]
+	if !ok {
+		return StatusUnknown
+	}
+	return s
+}
+
+func (h *HealthChecker) broadcast(status Status) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.watchers {
+		select {
+		case ch <- status:
+		default:
+		}
+	}
+}
+
+func (h *HealthChecker) handleHealthz(c *Context) {
+	service := c.Request.URL.Query().Get("service")
+	status := h.status(service)
+	if status != StatusServing {
+		c.Writer.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = c.Writer.Write([]byte(status.String()))
+}
+
+func (h *HealthChecker) handleLivez(c *Context) {
+	_, _ = c.Writer.Write([]byte("ok"))
+}
+
+func (h *HealthChecker) handleReadyz(c *Context) {
+	if h.status("") == StatusNotServing {
+		c.Writer.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	h.mu.RLock()
+	probes := append([]Probe(nil), h.probes...)
+	h.mu.RUnlock()
+
+	for _, p := range probes {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), p.timeout)
+		errCh := make(chan error, 1)
+		go func(fn func(context.Context) error) { errCh <- fn(ctx) }(p.fn)
+		var err error
+		select {
+		case err = <-errCh:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		cancel()
+		if err != nil {
+			c.Writer.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = c.Writer.Write([]byte(p.name + ": " + err.Error()))
+			return
+		}
+	}
+	_, _ = c.Writer.Write([]byte("ok"))
+}
+
+func (h *HealthChecker) handleWatch(c *Context) {
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		c.Writer.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	ch := make(chan Status, 1)
+	h.mu.Lock()
+	h.watchers[ch] = struct{}{}
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.watchers, ch)
+		h.mu.Unlock()
+	}()
+
+	writeEvent(c.Writer, flusher, h.status(""))
+	for {
+		select {
+		case status := <-ch:
+			writeEvent(c.Writer, flusher, status)
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, status Status) {
+	_, _ = w.Write([]byte("data: " + status.String() + "\n\n"))
+	flusher.Flush()
+}
+
+func isHealthPath(path string) bool {
+	switch path {
+	case healthzPath, healthzWatchPath, livezPath, readyzPath:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,76 @@
+package znet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatusString(t *testing.T) {
+	cases := map[Status]string{
+		StatusServing:    "SERVING",
+		StatusNotServing: "NOT_SERVING",
+		StatusUnknown:    "UNKNOWN",
+		Status(99):       "UNKNOWN",
+	}
+	for status, want := range cases {
+		if got := status.String(); got != want {
+			t.Errorf("Status(%d).String() = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestHealthCheckerSetServingStatus(t *testing.T) {
+	h := &HealthChecker{
+		statuses: map[string]Status{"": StatusServing},
+		watchers: map[chan Status]struct{}{},
+	}
+
+	if got := h.status(""); got != StatusServing {
+		t.Fatalf("initial status = %v, want StatusServing", got)
+	}
+	if got := h.status("missing"); got != StatusUnknown {
+		t.Fatalf("status of unregistered service = %v, want StatusUnknown", got)
+	}
+
+	ch := make(chan Status, 1)
+	h.mu.Lock()
+	h.watchers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	h.SetServingStatus("", StatusNotServing)
+
+	if got := h.status(""); got != StatusNotServing {
+		t.Fatalf("status after SetServingStatus = %v, want StatusNotServing", got)
+	}
+	select {
+	case got := <-ch:
+		if got != StatusNotServing {
+			t.Fatalf("broadcast status = %v, want StatusNotServing", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SetServingStatus did not broadcast to watcher")
+	}
+}
+
+func TestHealthCheckerRegister(t *testing.T) {
+	h := &HealthChecker{statuses: map[string]Status{}}
+	h.Register("ok", func(context.Context) error { return nil })
+	h.Register("custom-timeout", func(context.Context) error {
+		return errors.New("boom")
+	}, time.Millisecond)
+
+	if len(h.probes) != 2 {
+		t.Fatalf("len(probes) = %d, want 2", len(h.probes))
+	}
+	if h.probes[0].timeout != defaultProbeTimeout {
+		t.Errorf("probes[0].timeout = %v, want default %v", h.probes[0].timeout, defaultProbeTimeout)
+	}
+	if h.probes[1].timeout != time.Millisecond {
+		t.Errorf("probes[1].timeout = %v, want 1ms", h.probes[1].timeout)
+	}
+	if err := h.probes[1].fn(context.Background()); err == nil {
+		t.Error("probes[1].fn returned nil error, want boom")
+	}
+}
@@ -0,0 +1,103 @@
+package znet
+
+import (
+	"net"
+	"sync/atomic"
+
+	"golang.org/x/net/netutil"
+	"golang.org/x/time/rate"
+)
+
+type (
+	// Stats is a snapshot of Engine.Stats, current conns and rejected count
+	// are cumulative across every address the Engine listens on.
+	Stats struct {
+		Conns      int64
+		Rejected   int64
+		AcceptRate float64
+	}
+	connStats struct {
+		conns    int64
+		rejected int64
+	}
+	// trackedListener counts live connections for Stats and, when limiter
+	// is set, rejects (rather than queues) connections once the
+	// accept-rate token bucket is empty so the Engine sheds load instead
+	// of letting Accept's backlog grow unbounded.
+	trackedListener struct {
+		net.Listener
+		limiter *rate.Limiter
+		stats   *connStats
+	}
+	trackedConn struct {
+		net.Conn
+		stats *connStats
+	}
+)
+
+// SetMaxConns caps the number of simultaneous connections every address of
+// this Engine accepts, Run wraps each net.Listener with
+// netutil.LimitListener accordingly. 0 (the default) means unlimited.
+func (e *Engine) SetMaxConns(n int) {
+	e.maxConns = n
+}
+
+// SetAcceptRate token-bucket limits how fast new connections are accepted,
+// rps is the steady refill rate and burst the bucket size, connections
+// arriving once the bucket is empty are closed immediately rather than
+// queued. 0 rps (the default) disables accept-rate limiting.
+func (e *Engine) SetAcceptRate(rps, burst int) {
+	if rps <= 0 {
+		e.acceptLimiter = nil
+		return
+	}
+	e.acceptLimiter = rate.NewLimiter(rate.Limit(rps), burst)
+}
+
+// Stats returns a snapshot of the Engine's live connection metrics.
+func (e *Engine) Stats() Stats {
+	s := Stats{
+		Conns:    atomic.LoadInt64(&e.stats.conns),
+		Rejected: atomic.LoadInt64(&e.stats.rejected),
+	}
+	if e.acceptLimiter != nil {
+		s.AcceptRate = float64(e.acceptLimiter.Limit())
+	}
+	return s
+}
+
+// limitListener applies SetMaxConns (or the per-address override) to ln if
+// set, then always wraps the result in a trackedListener so Stats and
+// SetAcceptRate's accept-rate shedding work regardless of whether a
+// connection cap is configured.
+func (e *Engine) limitListener(ln net.Listener, maxConnsOverride int) net.Listener {
+	max := maxConnsOverride
+	if max <= 0 {
+		max = e.maxConns
+	}
+	if max > 0 {
+		ln = netutil.LimitListener(ln, max)
+	}
+	return &trackedListener{Listener: ln, limiter: e.acceptLimiter, stats: &e.stats}
+}
+
+func (l *trackedListener) Accept() (net.Conn, error) {
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.limiter != nil && !l.limiter.Allow() {
+			atomic.AddInt64(&l.stats.rejected, 1)
+			_ = c.Close()
+			continue
+		}
+		atomic.AddInt64(&l.stats.conns, 1)
+		return &trackedConn{Conn: c, stats: l.stats}, nil
+	}
+}
+
+func (c *trackedConn) Close() error {
+	atomic.AddInt64(&c.stats.conns, -1)
+	return c.Conn.Close()
+}
@@ -0,0 +1,89 @@
+package znet
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestTrackedListenerAcceptAndClose(t *testing.T) {
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer raw.Close()
+
+	var stats connStats
+	ln := &trackedListener{Listener: raw, stats: &stats}
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	dialConn, err := net.Dial("tcp", raw.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dialConn.Close()
+
+	var conn net.Conn
+	select {
+	case conn = <-accepted:
+	case <-time.After(time.Second):
+		t.Fatal("Accept did not return in time")
+	}
+	if got := stats.conns; got != 1 {
+		t.Fatalf("stats.conns after Accept = %d, want 1", got)
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := stats.conns; got != 0 {
+		t.Fatalf("stats.conns after Close = %d, want 0", got)
+	}
+}
+
+func TestTrackedListenerRejectsOverLimiter(t *testing.T) {
+	raw, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer raw.Close()
+
+	var stats connStats
+	ln := &trackedListener{Listener: raw, limiter: rate.NewLimiter(0, 0), stats: &stats}
+
+	accepted := make(chan error, 1)
+	go func() {
+		_, err := ln.Accept()
+		accepted <- err
+	}()
+
+	conn, err := net.Dial("tcp", raw.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The rejected connection is closed server-side rather than handed
+	// back, so Accept keeps blocking for the next one instead of returning.
+	select {
+	case <-accepted:
+		t.Fatal("Accept returned for a connection the limiter should reject")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if got := stats.rejected; got != 1 {
+		t.Fatalf("stats.rejected = %d, want 1", got)
+	}
+	if got := stats.conns; got != 0 {
+		t.Fatalf("stats.conns = %d, want 0", got)
+	}
+}
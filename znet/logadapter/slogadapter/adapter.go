@@ -0,0 +1,51 @@
+// Package slogadapter adapts the standard library's log/slog to znet.Logger.
+package slogadapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/sohaha/zlsgo/znet"
+)
+
+type adapter struct {
+	l *slog.Logger
+}
+
+// New wraps l as a znet.Logger.
+func New(l *slog.Logger) znet.Logger {
+	return &adapter{l: l}
+}
+
+func (a *adapter) With(fields ...znet.Field) znet.Logger {
+	args := make([]interface{}, 0, len(fields)*2)
+	for _, f := range fields {
+		args = append(args, f.Key, f.Value)
+	}
+	return &adapter{l: a.l.With(args...)}
+}
+
+func (a *adapter) Debugf(format string, args ...interface{}) {
+	a.l.Log(context.Background(), slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+func (a *adapter) Infof(format string, args ...interface{}) {
+	a.l.Log(context.Background(), slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func (a *adapter) Warnf(format string, args ...interface{}) {
+	a.l.Log(context.Background(), slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+func (a *adapter) Errorf(format string, args ...interface{}) {
+	a.l.Log(context.Background(), slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+// Fatalf logs at error level and terminates the process, matching the
+// zlog and zerolog adapters' Fatalf.
+func (a *adapter) Fatalf(format string, args ...interface{}) {
+	a.l.Log(context.Background(), slog.LevelError, fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
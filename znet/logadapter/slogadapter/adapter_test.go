@@ -0,0 +1,45 @@
+package slogadapter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/sohaha/zlsgo/znet"
+)
+
+func TestAdapterLogsFormattedMessageAndFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	l.With(znet.F("request_id", "abc")).Infof("hello %s", "world")
+
+	out := buf.String()
+	if !strings.Contains(out, "msg=\"hello world\"") {
+		t.Errorf("output missing formatted message: %s", out)
+	}
+	if !strings.Contains(out, "request_id=abc") {
+		t.Errorf("output missing field attached via With: %s", out)
+	}
+	if !strings.Contains(out, "level=INFO") {
+		t.Errorf("output missing expected level: %s", out)
+	}
+}
+
+func TestAdapterWithDoesNotMutateReceiver(t *testing.T) {
+	var buf bytes.Buffer
+	base := New(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	base.With(znet.F("k", "v")).Infof("child")
+	base.Infof("parent")
+
+	out := buf.String()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), out)
+	}
+	if strings.Contains(lines[1], "k=v") {
+		t.Errorf("With mutated the base logger: %s", lines[1])
+	}
+}
@@ -0,0 +1,32 @@
+// Package zerologadapter adapts github.com/rs/zerolog to znet.Logger so an
+// Engine can ship JSON logs without znet depending on zerolog itself.
+package zerologadapter
+
+import (
+	"github.com/rs/zerolog"
+
+	"github.com/sohaha/zlsgo/znet"
+)
+
+type adapter struct {
+	l zerolog.Logger
+}
+
+// New wraps l as a znet.Logger.
+func New(l zerolog.Logger) znet.Logger {
+	return &adapter{l: l}
+}
+
+func (a *adapter) With(fields ...znet.Field) znet.Logger {
+	ctx := a.l.With()
+	for _, f := range fields {
+		ctx = ctx.Interface(f.Key, f.Value)
+	}
+	return &adapter{l: ctx.Logger()}
+}
+
+func (a *adapter) Debugf(format string, args ...interface{}) { a.l.Debug().Msgf(format, args...) }
+func (a *adapter) Infof(format string, args ...interface{})  { a.l.Info().Msgf(format, args...) }
+func (a *adapter) Warnf(format string, args ...interface{})  { a.l.Warn().Msgf(format, args...) }
+func (a *adapter) Errorf(format string, args ...interface{}) { a.l.Error().Msgf(format, args...) }
+func (a *adapter) Fatalf(format string, args ...interface{}) { a.l.Fatal().Msgf(format, args...) }
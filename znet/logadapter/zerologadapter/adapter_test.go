@@ -0,0 +1,45 @@
+package zerologadapter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/sohaha/zlsgo/znet"
+)
+
+func TestAdapterLogsFormattedMessageAndFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(zerolog.New(&buf))
+
+	l.With(znet.F("request_id", "abc")).Infof("hello %s", "world")
+
+	out := buf.String()
+	if !strings.Contains(out, `"message":"hello world"`) {
+		t.Errorf("output missing formatted message: %s", out)
+	}
+	if !strings.Contains(out, `"request_id":"abc"`) {
+		t.Errorf("output missing field attached via With: %s", out)
+	}
+	if !strings.Contains(out, `"level":"info"`) {
+		t.Errorf("output missing expected level: %s", out)
+	}
+}
+
+func TestAdapterWithDoesNotMutateReceiver(t *testing.T) {
+	var buf bytes.Buffer
+	base := New(zerolog.New(&buf))
+
+	base.With(znet.F("k", "v")).Infof("child")
+	base.Infof("parent")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	if strings.Contains(lines[1], `"k":"v"`) {
+		t.Errorf("With mutated the base logger: %s", lines[1])
+	}
+}
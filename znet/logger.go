@@ -0,0 +1,124 @@
+package znet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sohaha/zlsgo/zlog"
+)
+
+type (
+	// Logger is the structured logging contract Engine and Context depend on,
+	// it lets callers swap zlog for zerolog, slog, or anything else without
+	// touching znet itself. See the zlog adapter below for the default, and
+	// znet/logadapter for zerolog/slog adapters.
+	Logger interface {
+		Debugf(format string, args ...interface{})
+		Infof(format string, args ...interface{})
+		Warnf(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+		Fatalf(format string, args ...interface{})
+		// With returns a Logger that attaches fields to every subsequent
+		// call, it does not mutate the receiver.
+		With(fields ...Field) Logger
+	}
+	// Field is a structured key/value pair attached to a log line.
+	Field struct {
+		Key   string
+		Value interface{}
+	}
+	// zlogAdapter is the default Logger, it wraps the concrete *zlog.Logger
+	// Engine already carries so existing behavior keeps working unchanged.
+	// It talks to it through zlogLike rather than *zlog.Logger directly so
+	// tests can substitute a fake.
+	zlogAdapter struct {
+		l      zlogLike
+		fields []Field
+	}
+	// zlogLike is the subset of *zlog.Logger's printf-style methods
+	// zlogAdapter calls through.
+	zlogLike interface {
+		Debugf(format string, args ...interface{})
+		Infof(format string, args ...interface{})
+		Warnf(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+		Fatalf(format string, args ...interface{})
+	}
+)
+
+// F builds a Field, use it with Logger.With.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// newZlogAdapter wraps l as the default Logger implementation.
+func newZlogAdapter(l *zlog.Logger) Logger {
+	return &zlogAdapter{l: l}
+}
+
+func (z *zlogAdapter) With(fields ...Field) Logger {
+	merged := make([]Field, 0, len(z.fields)+len(fields))
+	merged = append(merged, z.fields...)
+	merged = append(merged, fields...)
+	return &zlogAdapter{l: z.l, fields: merged}
+}
+
+func (z *zlogAdapter) Debugf(format string, args ...interface{}) {
+	z.l.Debugf("%s", z.withFields(format, args...))
+}
+
+func (z *zlogAdapter) Infof(format string, args ...interface{}) {
+	z.l.Infof("%s", z.withFields(format, args...))
+}
+
+func (z *zlogAdapter) Warnf(format string, args ...interface{}) {
+	z.l.Warnf("%s", z.withFields(format, args...))
+}
+
+func (z *zlogAdapter) Errorf(format string, args ...interface{}) {
+	z.l.Errorf("%s", z.withFields(format, args...))
+}
+
+func (z *zlogAdapter) Fatalf(format string, args ...interface{}) {
+	z.l.Fatalf("%s", z.withFields(format, args...))
+}
+
+// withFields renders format/args and appends "key=value" pairs so the
+// default adapter keeps plain-text behavior while still carrying the
+// structured fields callers attached via With.
+func (z *zlogAdapter) withFields(format string, args ...interface{}) string {
+	msg := fmt.Sprintf(format, args...)
+	if len(z.fields) == 0 {
+		return msg
+	}
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, f := range z.fields {
+		b.WriteString(fmt.Sprintf(" %s=%v", f.Key, f.Value))
+	}
+	return b.String()
+}
+
+// AccessLog is a built-in middleware that emits one structured log line per
+// request via Context.Log, carrying the method, path, status, latency,
+// remote address and request id so JSON-backed adapters can ship it straight
+// to a log aggregator without post-parsing.
+func AccessLog() MiddlewareFunc {
+	return func(c *Context, fn HandlerFunc) {
+		fn(c)
+		latency := time.Since(c.startTime)
+		reqID := c.Request.Header.Get("X-Request-Id")
+		if reqID == "" {
+			reqID = "-"
+		}
+		c.Log.With(
+			F("method", c.Request.Method),
+			F("path", c.Request.URL.Path),
+			F("status", c.Code),
+			F("latency", latency.String()),
+			F("remote", c.Request.RemoteAddr),
+			F("request_id", reqID),
+		).Infof("access")
+	}
+}
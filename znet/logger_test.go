@@ -0,0 +1,76 @@
+package znet
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeZlog records the last format/args it was called with, standing in for
+// *zlog.Logger so tests can check what zlogAdapter hands it without
+// depending on the real logger's output format.
+type fakeZlog struct {
+	lastFormat string
+	lastArgs   []interface{}
+}
+
+func (f *fakeZlog) Debugf(format string, args ...interface{}) { f.record(format, args) }
+func (f *fakeZlog) Infof(format string, args ...interface{})  { f.record(format, args) }
+func (f *fakeZlog) Warnf(format string, args ...interface{})  { f.record(format, args) }
+func (f *fakeZlog) Errorf(format string, args ...interface{}) { f.record(format, args) }
+func (f *fakeZlog) Fatalf(format string, args ...interface{}) { f.record(format, args) }
+
+func (f *fakeZlog) record(format string, args []interface{}) {
+	f.lastFormat = format
+	f.lastArgs = args
+}
+
+func TestZlogAdapterWithFields(t *testing.T) {
+	a := &zlogAdapter{}
+
+	if got := a.withFields("hello %s", "world"); got != "hello world" {
+		t.Errorf("withFields with no fields = %q, want %q", got, "hello world")
+	}
+
+	withF := a.With(F("k1", "v1"), F("k2", 2)).(*zlogAdapter)
+	if got, want := withF.withFields("msg"), "msg k1=v1 k2=2"; got != want {
+		t.Errorf("withFields with fields = %q, want %q", got, want)
+	}
+}
+
+func TestZlogAdapterWithDoesNotMutateReceiver(t *testing.T) {
+	base := &zlogAdapter{}
+	withF := base.With(F("k", "v")).(*zlogAdapter)
+
+	if len(base.fields) != 0 {
+		t.Fatalf("With mutated the receiver's fields: %v", base.fields)
+	}
+	if len(withF.fields) != 1 {
+		t.Fatalf("len(withF.fields) = %d, want 1", len(withF.fields))
+	}
+
+	chained := withF.With(F("k2", "v2")).(*zlogAdapter)
+	if len(withF.fields) != 1 {
+		t.Fatalf("chained With mutated its receiver's fields: %v", withF.fields)
+	}
+	if len(chained.fields) != 2 {
+		t.Fatalf("len(chained.fields) = %d, want 2", len(chained.fields))
+	}
+}
+
+// TestZlogAdapterDoesNotDoubleFormatRenderedMessage guards against
+// re-Sprintf-ing an already-rendered message: a literal "%" surviving in a
+// field value or the formatted message (e.g. from a URL or an error's
+// Error() text) must not be reinterpreted as a verb by the underlying
+// logger's own printf-style call.
+func TestZlogAdapterDoesNotDoubleFormatRenderedMessage(t *testing.T) {
+	fake := &fakeZlog{}
+	l := &zlogAdapter{l: fake}
+
+	l.Infof("access path=%s", "/search?q=100%off")
+
+	rendered := fmt.Sprintf(fake.lastFormat, fake.lastArgs...)
+	if !strings.Contains(rendered, "100%off") {
+		t.Errorf("literal %% was reinterpreted: got %q", rendered)
+	}
+}
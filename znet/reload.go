@@ -0,0 +1,193 @@
+package znet
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var errNotFileListener = errors.New("znet: listener does not support File()")
+
+const (
+	// listenFDsEnv tells a freshly exec'd child how many listening sockets
+	// it inherited, starting at fd 4 (0-2 are stdin/stdout/stderr, 3 is the
+	// readiness pipe).
+	listenFDsEnv  = "ZNET_LISTEN_FDS"
+	readyFD       = 3
+	firstSocketFD = 4
+	// childReadyTimeout bounds how long doReload waits for the child to
+	// signal readiness before giving up so a bad child can't wedge every
+	// later reload attempt.
+	childReadyTimeout = 30 * time.Second
+)
+
+var (
+	reloadSignal   os.Signal = defaultReloadSignal()
+	reloadSignalCh           = make(chan os.Signal, 1)
+	reloadWatch    sync.Once
+	inheritedFDs   int
+)
+
+func init() {
+	if n, err := strconv.Atoi(os.Getenv(listenFDsEnv)); err == nil && n > 0 {
+		inheritedFDs = n
+	}
+}
+
+// SetReloadSignal overrides the signal that triggers a zero-downtime reload,
+// the default is SIGHUP (os.Interrupt on Windows). Call it before Run.
+//
+// The watched signal is process-wide, not per-Engine, since one signal
+// handler drains every running Engine together - if more than one Engine
+// calls SetReloadSignal, the last call before Run wins for all of them.
+func (e *Engine) SetReloadSignal(sig os.Signal) {
+	reloadSignal = sig
+}
+
+// Reload forks a child process that inherits all listening sockets of every
+// running Engine and hands traffic over once the child reports it is ready,
+// draining this process with the same timeout Run uses on shutdown.
+func (e *Engine) Reload() error {
+	return doReload()
+}
+
+// listen returns the net.Listener for addr, either inherited from a parent
+// process via ExtraFiles (when ZNET_LISTEN_FDS is set) or freshly opened with
+// SO_REUSEPORT so a future reload can bind the same address before the
+// current process stops accepting on it.
+func listen(network, addr string, index int) (net.Listener, error) {
+	if index < inheritedFDs {
+		f := os.NewFile(uintptr(firstSocketFD+index), "znet-inherited-"+addr)
+		ln, err := net.FileListener(f)
+		if err == nil {
+			_ = f.Close()
+			return ln, nil
+		}
+	}
+	return listenReusePort(network, addr)
+}
+
+// watchReload registers the process-wide signal handler that triggers Reload,
+// once, on the first Run.
+// CloseHotRestart skips registering it, an explicit call to Reload still
+// works regardless.
+func watchReload() {
+	if CloseHotRestart {
+		return
+	}
+	reloadWatch.Do(func() {
+		signal.Notify(reloadSignalCh, reloadSignal)
+		go func() {
+			for range reloadSignalCh {
+				if err := doReload(); err != nil {
+					Log.Errorf("Reload: %s\n", err)
+				}
+			}
+		}()
+	})
+}
+
+// doReload forks a copy of the current executable, passing every listening
+// socket plus a readiness pipe through ExtraFiles, and waits for the child to
+// signal it is accepting connections before this process starts draining.
+func doReload() error {
+	runningMu.Lock()
+	listeners := append([]net.Listener(nil), runningListeners...)
+	runningMu.Unlock()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	files := make([]*os.File, 0, len(listeners)+1)
+	files = append(files, w)
+	for _, ln := range listeners {
+		f, err := fileFromListener(ln)
+		if err != nil {
+			_ = w.Close()
+			return err
+		}
+		files = append(files, f)
+	}
+
+	env := append(os.Environ(), listenFDsEnv+"="+strconv.Itoa(len(listeners)))
+	execPath, err := os.Executable()
+	if err != nil {
+		_ = w.Close()
+		return err
+	}
+
+	p, err := os.StartProcess(execPath, os.Args, &os.ProcAttr{
+		Env:   env,
+		Files: append([]*os.File{os.Stdin, os.Stdout, os.Stderr}, files...),
+	})
+	_ = w.Close()
+	if err != nil {
+		return err
+	}
+
+	if err := r.SetReadDeadline(time.Now().Add(childReadyTimeout)); err != nil {
+		return err
+	}
+	buf := make([]byte, 1)
+	if _, err := r.Read(buf); err != nil {
+		_ = p.Kill()
+		return fmt.Errorf("znet: child pid %d did not become ready: %w", p.Pid, err)
+	}
+
+	Log.Successf("Reload: child pid %d is ready, draining this process\n", p.Pid)
+	runningMu.Lock()
+	wg := runningWG
+	runningMu.Unlock()
+	drainServers(false, wg)
+	if wg != nil {
+		wg.Wait()
+	}
+	if ShutdownDone != nil {
+		ShutdownDone()
+	}
+
+	// The child already owns every listening socket, so this process has
+	// nothing left to serve - exit instead of letting Run's goroutine sit
+	// blocked on isKill() forever as a drained-but-resident zombie.
+	os.Exit(0)
+	return nil
+}
+
+// signalReady tells a parent process, if any, that this child finished
+// starting every listener and is ready to take over traffic.
+func signalReady() {
+	if inheritedFDs == 0 {
+		return
+	}
+	f := os.NewFile(readyFD, "znet-ready")
+	if f == nil {
+		return
+	}
+	_, _ = f.Write([]byte{1})
+	_ = f.Close()
+}
+
+func defaultReloadSignal() os.Signal {
+	return hangupSignal
+}
+
+// fileFromListener duplicates the listener's underlying socket into an
+// *os.File so it can be passed to a child process through ExtraFiles.
+func fileFromListener(ln net.Listener) (*os.File, error) {
+	type filer interface {
+		File() (*os.File, error)
+	}
+	f, ok := ln.(filer)
+	if !ok {
+		return nil, errNotFileListener
+	}
+	return f.File()
+}
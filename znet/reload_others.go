@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package znet
+
+import (
+	"net"
+	"os"
+)
+
+var hangupSignal os.Signal = os.Interrupt
+
+// listenReusePort falls back to a plain listen, Windows has no SO_REUSEPORT.
+// TCP listeners on Windows cannot be duplicated into an *os.File, so Reload
+// returns an error there instead of handing the sockets to a child.
+func listenReusePort(network, addr string) (net.Listener, error) {
+	return net.Listen(network, addr)
+}
@@ -0,0 +1,61 @@
+package znet
+
+import (
+	"net"
+	"testing"
+)
+
+// fakeListener satisfies net.Listener but not the File() (*os.File, error)
+// interface fileFromListener needs, unlike the *net.TCPListener Run hands
+// it in practice.
+type fakeListener struct{}
+
+func (fakeListener) Accept() (net.Conn, error) { return nil, net.ErrClosed }
+func (fakeListener) Close() error              { return nil }
+func (fakeListener) Addr() net.Addr            { return nil }
+
+func TestFileFromListenerRejectsNonFileListener(t *testing.T) {
+	if _, err := fileFromListener(fakeListener{}); err != errNotFileListener {
+		t.Errorf("fileFromListener(fakeListener{}) err = %v, want %v", err, errNotFileListener)
+	}
+}
+
+func TestFileFromListenerAcceptsTCPListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	f, err := fileFromListener(ln)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+}
+
+func TestListenFallsBackToReusePortWithoutInheritedFDs(t *testing.T) {
+	old := inheritedFDs
+	inheritedFDs = 0
+	defer func() { inheritedFDs = old }()
+
+	ln, err := listen("tcp", "127.0.0.1:0", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+}
+
+func TestWatchReloadSkipsSignalHandlerWhenCloseHotRestart(t *testing.T) {
+	old := CloseHotRestart
+	CloseHotRestart = true
+	defer func() { CloseHotRestart = old }()
+
+	watchReload()
+
+	ran := false
+	reloadWatch.Do(func() { ran = true })
+	if !ran {
+		t.Error("watchReload registered the SIGHUP handler even though CloseHotRestart was true")
+	}
+}
@@ -0,0 +1,32 @@
+//go:build linux || darwin || freebsd || netbsd || dragonfly || openbsd
+// +build linux darwin freebsd netbsd dragonfly openbsd
+
+package znet
+
+import (
+	"context"
+	"net"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+var hangupSignal = syscall.SIGHUP
+
+// listenReusePort opens addr with SO_REUSEPORT so a reloaded child can bind
+// the same address before this process stops accepting on it.
+func listenReusePort(network, addr string) (net.Listener, error) {
+	lc := net.ListenConfig{
+		Control: func(_, _ string, c syscall.RawConn) error {
+			var setErr error
+			err := c.Control(func(fd uintptr) {
+				setErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1)
+			})
+			if err != nil {
+				return err
+			}
+			return setErr
+		},
+	}
+	return lc.Listen(context.Background(), network, addr)
+}